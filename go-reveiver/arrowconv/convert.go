@@ -0,0 +1,87 @@
+// Package arrowconv 把一个 Arrow IPC stream 转换成 JSON、NDJSON 或 CSV，
+// 以增量、不缓冲整个数据集的方式写出，供没有 Arrow 客户端的消费者使用。
+package arrowconv
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/apache/arrow/go/v18/arrow/ipc"
+)
+
+// Format 是 /convert 支持的输出格式
+type Format string
+
+const (
+	// FormatJSON 把所有批次合并成一个 JSON 数组
+	FormatJSON Format = "json"
+	// FormatNDJSON 每行输出一个 JSON 对象（换行分隔 JSON）
+	FormatNDJSON Format = "ndjson"
+	// FormatCSV 输出带表头的 CSV，嵌套类型（Struct/List/Map）编码为 JSON 字符串
+	FormatCSV Format = "csv"
+)
+
+// NegotiateFormat 根据 Accept 请求头选择输出格式，默认回退到 NDJSON，
+// 因为 NDJSON 最适合流式、逐批次写出而不必等待整个数据集。
+func NegotiateFormat(accept string) Format {
+	for _, part := range strings.Split(accept, ",") {
+		mt, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mt {
+		case "application/json":
+			return FormatJSON
+		case "application/x-ndjson", "application/jsonlines":
+			return FormatNDJSON
+		case "text/csv":
+			return FormatCSV
+		}
+	}
+	return FormatNDJSON
+}
+
+// Flusher 是 http.Flusher 的最小子集，Convert 在每个批次写完后调用它，
+// 让调用方（例如 Gin 的 chunked response）及时把数据推给客户端。
+type Flusher interface {
+	Flush()
+}
+
+// noopFlusher 在调用方没有提供 Flusher 时使用
+type noopFlusher struct{}
+
+func (noopFlusher) Flush() {}
+
+// Convert 从 r 读取一个 Arrow IPC stream，按 format 编码后写入 w，
+// 每处理完一个 record batch 就调用一次 flush，不在内存中累积整个数据集。
+func Convert(r io.Reader, w io.Writer, format Format, flush Flusher) error {
+	if flush == nil {
+		flush = noopFlusher{}
+	}
+
+	reader, err := ipc.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("创建 Arrow IPC reader 失败: %v", err)
+	}
+	defer reader.Release()
+
+	enc, err := newEncoder(format, w, reader.Schema())
+	if err != nil {
+		return err
+	}
+
+	for reader.Next() {
+		record := reader.Record()
+		if err := enc.writeRecord(record); err != nil {
+			return fmt.Errorf("编码批次失败: %v", err)
+		}
+		flush.Flush()
+	}
+	if err := reader.Err(); err != nil {
+		return fmt.Errorf("读取 Arrow 批次失败: %v", err)
+	}
+
+	return enc.close()
+}
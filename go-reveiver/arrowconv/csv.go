@@ -0,0 +1,85 @@
+package arrowconv
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow/go/v18/arrow"
+)
+
+// csvEncoder 先写一次表头行，之后每个输入行对应一行 CSV。嵌套值（Struct/List/Map）
+// 没有对应的 CSV 原生表示，所以按 JSON 字符串写入单元格
+type csvEncoder struct {
+	w           *csv.Writer
+	columns     []string
+	wroteHeader bool
+}
+
+func newCSVEncoder(w io.Writer, schema *arrow.Schema) *csvEncoder {
+	columns := make([]string, schema.NumFields())
+	for i, f := range schema.Fields() {
+		columns[i] = f.Name
+	}
+	return &csvEncoder{w: csv.NewWriter(w), columns: columns}
+}
+
+func (e *csvEncoder) writeRecord(record arrow.Record) error {
+	if !e.wroteHeader {
+		if err := e.w.Write(e.columns); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+
+	rows, err := rowsOf(record)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		cells := make([]string, len(e.columns))
+		for i, col := range e.columns {
+			cells[i], err = cellString(row[col])
+			if err != nil {
+				return err
+			}
+		}
+		if err := e.w.Write(cells); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvEncoder) close() error {
+	if !e.wroteHeader {
+		if err := e.w.Write(e.columns); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// cellString 把一个提取出来的值渲染成 CSV 单元格：标量直接打印，
+// 嵌套/复合值则回退成 JSON 表示
+func cellString(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return "", fmt.Errorf("序列化单元格失败: %v", err)
+		}
+		return string(b), nil
+	default:
+		return fmt.Sprintf("%v", val), nil
+	}
+}
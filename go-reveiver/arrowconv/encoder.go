@@ -0,0 +1,49 @@
+package arrowconv
+
+import (
+	"io"
+
+	"github.com/apache/arrow/go/v18/arrow"
+)
+
+// recordEncoder 把同一个 Arrow schema 的行按某种具体的线上格式流式输出
+type recordEncoder interface {
+	writeRecord(record arrow.Record) error
+	close() error
+}
+
+// newEncoder 按 format 构造对应的 recordEncoder，绑定到 schema 和 w
+func newEncoder(format Format, w io.Writer, schema *arrow.Schema) (recordEncoder, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonEncoder{w: w, asArray: true}, nil
+	case FormatNDJSON:
+		return &jsonEncoder{w: w, asArray: false}, nil
+	case FormatCSV:
+		return newCSVEncoder(w, schema), nil
+	default:
+		return &jsonEncoder{w: w, asArray: false}, nil
+	}
+}
+
+// rowsOf 把一个 record batch 的每一行转换成 name -> value 的 map，供 JSON/CSV 编码器复用
+func rowsOf(record arrow.Record) ([]map[string]interface{}, error) {
+	schema := record.Schema()
+	numRows := int(record.NumRows())
+	numCols := int(record.NumCols())
+
+	rows := make([]map[string]interface{}, numRows)
+	for i := 0; i < numRows; i++ {
+		row := make(map[string]interface{}, numCols)
+		for j := 0; j < numCols; j++ {
+			field := schema.Field(j)
+			v, err := extractValue(record.Column(j), field, i)
+			if err != nil {
+				return nil, err
+			}
+			row[field.Name] = v
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
@@ -0,0 +1,64 @@
+package arrowconv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow/go/v18/arrow"
+)
+
+// jsonEncoder 把每个到达的 batch 按行写出：asArray 为 true 时写成一个大 JSON 数组，
+// 否则按 NDJSON（每行一个 JSON 对象）逐行输出
+type jsonEncoder struct {
+	w        io.Writer
+	asArray  bool
+	wroteAny bool
+}
+
+func (e *jsonEncoder) writeRecord(record arrow.Record) error {
+	rows, err := rowsOf(record)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if e.asArray {
+			prefix := ","
+			if !e.wroteAny {
+				prefix = "["
+			}
+			if _, err := io.WriteString(e.w, prefix); err != nil {
+				return err
+			}
+		}
+
+		b, err := json.Marshal(row)
+		if err != nil {
+			return fmt.Errorf("序列化行失败: %v", err)
+		}
+		if _, err := e.w.Write(b); err != nil {
+			return err
+		}
+
+		if !e.asArray {
+			if _, err := io.WriteString(e.w, "\n"); err != nil {
+				return err
+			}
+		}
+		e.wroteAny = true
+	}
+	return nil
+}
+
+func (e *jsonEncoder) close() error {
+	if e.asArray {
+		if !e.wroteAny {
+			_, err := io.WriteString(e.w, "[]")
+			return err
+		}
+		_, err := io.WriteString(e.w, "]")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package arrowconv
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/arrow/go/v18/arrow"
+	"github.com/apache/arrow/go/v18/arrow/array"
+	"github.com/apache/arrow/go/v18/arrow/decimal128"
+	"github.com/apache/arrow/go/v18/arrow/decimal256"
+)
+
+// extractValue 把 arr 在 rowIndex 处的值转换成一个可被 encoding/json 序列化的
+// Go 值。嵌套类型（Struct/List/Map）和 Dictionary 递归处理，Decimal 按字符串输出
+// 以保留精度，Timestamp/Date 按 RFC3339 / ISO 日期字符串输出。
+func extractValue(arr arrow.Array, field arrow.Field, rowIndex int) (interface{}, error) {
+	if arr.IsNull(rowIndex) {
+		return nil, nil
+	}
+
+	switch a := arr.(type) {
+	case *array.Boolean:
+		return a.Value(rowIndex), nil
+	case *array.Int8:
+		return a.Value(rowIndex), nil
+	case *array.Int16:
+		return a.Value(rowIndex), nil
+	case *array.Int32:
+		return a.Value(rowIndex), nil
+	case *array.Int64:
+		return a.Value(rowIndex), nil
+	case *array.Uint8:
+		return a.Value(rowIndex), nil
+	case *array.Uint16:
+		return a.Value(rowIndex), nil
+	case *array.Uint32:
+		return a.Value(rowIndex), nil
+	case *array.Uint64:
+		return a.Value(rowIndex), nil
+	case *array.Float32:
+		return a.Value(rowIndex), nil
+	case *array.Float64:
+		return a.Value(rowIndex), nil
+	case *array.String:
+		return a.Value(rowIndex), nil
+	case *array.LargeString:
+		return a.Value(rowIndex), nil
+	case *array.Binary:
+		return a.Value(rowIndex), nil
+
+	case *array.Decimal128:
+		dt := field.Type.(*arrow.Decimal128Type)
+		return decimal128ToString(a.Value(rowIndex), dt.Scale), nil
+	case *array.Decimal256:
+		dt := field.Type.(*arrow.Decimal256Type)
+		return decimal256ToString(a.Value(rowIndex), dt.Scale), nil
+
+	case *array.Date32:
+		return a.Value(rowIndex).ToTime().Format("2006-01-02"), nil
+	case *array.Date64:
+		return a.Value(rowIndex).ToTime().Format("2006-01-02"), nil
+
+	case *array.Timestamp:
+		dt := field.Type.(*arrow.TimestampType)
+		t := a.Value(rowIndex).ToTime(dt.Unit)
+		if dt.TimeZone != "" {
+			loc, err := time.LoadLocation(dt.TimeZone)
+			if err == nil {
+				t = t.In(loc)
+			}
+		}
+		return t.Format(time.RFC3339Nano), nil
+
+	case *array.Dictionary:
+		dt := field.Type.(*arrow.DictionaryType)
+		dictField := arrow.Field{Name: field.Name, Type: dt.ValueType, Nullable: field.Nullable}
+		return extractValue(a.Dictionary(), dictField, a.GetValueIndex(rowIndex))
+
+	case *array.Struct:
+		structType := field.Type.(*arrow.StructType)
+		fields := structType.Fields()
+		out := make(map[string]interface{}, a.NumField())
+		for i := 0; i < a.NumField(); i++ {
+			v, err := extractValue(a.Field(i), fields[i], rowIndex)
+			if err != nil {
+				return nil, err
+			}
+			out[fields[i].Name] = v
+		}
+		return out, nil
+
+	case *array.List:
+		elemField := field.Type.(*arrow.ListType).ElemField()
+		start, end, ok := listOffsets(a, rowIndex)
+		if !ok {
+			return []interface{}{}, nil
+		}
+		values := a.ListValues()
+		out := make([]interface{}, 0, end-start)
+		for i := start; i < end; i++ {
+			v, err := extractValue(values, elemField, int(i))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+
+	case *array.Map:
+		mapType := field.Type.(*arrow.MapType)
+		start, end, ok := mapOffsets(a, rowIndex)
+		if !ok {
+			return []interface{}{}, nil
+		}
+		keys, items := a.Keys(), a.Items()
+		out := make([]interface{}, 0, end-start)
+		for i := start; i < end; i++ {
+			idx := int(i)
+			k, err := extractValue(keys, mapType.KeyField(), idx)
+			if err != nil {
+				return nil, err
+			}
+			v, err := extractValue(items, mapType.ItemField(), idx)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, map[string]interface{}{"key": k, "value": v})
+		}
+		return out, nil
+
+	default:
+		return nil, fmt.Errorf("不支持转换的 Arrow 类型: %T", arr)
+	}
+}
+
+func decimal128ToString(v decimal128.Num, scale int32) string {
+	return v.ToString(scale)
+}
+
+func decimal256ToString(v decimal256.Num, scale int32) string {
+	return v.ToString(scale)
+}
+
+func listOffsets(arr *array.List, rowIndex int) (start, end int64, ok bool) {
+	offsets := arr.Offsets()
+	if rowIndex+1 >= len(offsets) {
+		return 0, 0, false
+	}
+	return int64(offsets[rowIndex]), int64(offsets[rowIndex+1]), true
+}
+
+func mapOffsets(arr *array.Map, rowIndex int) (start, end int64, ok bool) {
+	offsets := arr.Offsets()
+	if rowIndex+1 >= len(offsets) {
+		return 0, 0, false
+	}
+	return int64(offsets[rowIndex]), int64(offsets[rowIndex+1]), true
+}
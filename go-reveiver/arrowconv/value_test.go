@@ -0,0 +1,144 @@
+package arrowconv
+
+import (
+	"testing"
+
+	"github.com/apache/arrow/go/v18/arrow"
+	"github.com/apache/arrow/go/v18/arrow/array"
+	"github.com/apache/arrow/go/v18/arrow/decimal128"
+	"github.com/apache/arrow/go/v18/arrow/memory"
+)
+
+func TestExtractValue_Int32(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	b := array.NewInt32Builder(pool)
+	defer b.Release()
+	b.Append(42)
+	arr := b.NewInt32Array()
+	defer arr.Release()
+
+	field := arrow.Field{Name: "v", Type: arrow.PrimitiveTypes.Int32}
+	v, err := extractValue(arr, field, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != int32(42) {
+		t.Fatalf("expected 42, got %v (%T)", v, v)
+	}
+}
+
+func TestExtractValue_Null(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	b := array.NewInt32Builder(pool)
+	defer b.Release()
+	b.AppendNull()
+	arr := b.NewInt32Array()
+	defer arr.Release()
+
+	field := arrow.Field{Name: "v", Type: arrow.PrimitiveTypes.Int32, Nullable: true}
+	v, err := extractValue(arr, field, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != nil {
+		t.Fatalf("expected nil for null value, got %v", v)
+	}
+}
+
+func TestExtractValue_String(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	b := array.NewStringBuilder(pool)
+	defer b.Release()
+	b.Append("hello")
+	arr := b.NewStringArray()
+	defer arr.Release()
+
+	field := arrow.Field{Name: "v", Type: arrow.BinaryTypes.String}
+	v, err := extractValue(arr, field, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != "hello" {
+		t.Fatalf("expected \"hello\", got %v", v)
+	}
+}
+
+func TestExtractValue_Decimal128(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	dt := &arrow.Decimal128Type{Precision: 10, Scale: 2}
+	b := array.NewDecimal128Builder(pool, dt)
+	defer b.Release()
+	b.Append(decimal128.FromI64(12345))
+	arr := b.NewDecimal128Array()
+	defer arr.Release()
+
+	field := arrow.Field{Name: "v", Type: dt}
+	v, err := extractValue(arr, field, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != "123.45" {
+		t.Fatalf("expected \"123.45\", got %v", v)
+	}
+}
+
+func TestExtractValue_List(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	lb := array.NewListBuilder(pool, arrow.PrimitiveTypes.Int32)
+	defer lb.Release()
+	vb := lb.ValueBuilder().(*array.Int32Builder)
+
+	lb.Append(true)
+	vb.Append(1)
+	vb.Append(2)
+	vb.Append(3)
+	arr := lb.NewListArray()
+	defer arr.Release()
+
+	listType := arrow.ListOf(arrow.PrimitiveTypes.Int32)
+	field := arrow.Field{Name: "v", Type: listType}
+	v, err := extractValue(arr, field, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, ok := v.([]interface{})
+	if !ok {
+		t.Fatalf("expected []interface{}, got %T", v)
+	}
+	if len(got) != 3 || got[0] != int32(1) || got[1] != int32(2) || got[2] != int32(3) {
+		t.Fatalf("unexpected list contents: %v", got)
+	}
+}
+
+func TestExtractValue_Struct(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	structType := arrow.StructOf(
+		arrow.Field{Name: "a", Type: arrow.PrimitiveTypes.Int32},
+		arrow.Field{Name: "b", Type: arrow.BinaryTypes.String},
+	)
+	sb := array.NewStructBuilder(pool, structType)
+	defer sb.Release()
+
+	ab := sb.FieldBuilder(0).(*array.Int32Builder)
+	bb := sb.FieldBuilder(1).(*array.StringBuilder)
+
+	sb.Append(true)
+	ab.Append(7)
+	bb.Append("x")
+
+	arr := sb.NewStructArray()
+	defer arr.Release()
+
+	field := arrow.Field{Name: "v", Type: structType}
+	v, err := extractValue(arr, field, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, ok := v.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected map[string]interface{}, got %T", v)
+	}
+	if got["a"] != int32(7) || got["b"] != "x" {
+		t.Fatalf("unexpected struct contents: %v", got)
+	}
+}
@@ -0,0 +1,27 @@
+// Package arrowio 提供辅助函数来自动识别 Arrow IPC 传输的物理编码（File vs Stream）
+// 和请求所用的压缩算法，让 /receive-arrow 在客户端没有精确声明时也能正确解码。
+package arrowio
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// fileMagic 是 Arrow File 格式开头和结尾都会出现的魔数
+const fileMagic = "ARROW1"
+
+// DetectReader 包装 r，探测它开头的字节是否匹配 Arrow File 格式的魔数 "ARROW1"。
+// 返回一个可以重新从头读取的 *bufio.Reader（已经 Peek 过，不会丢失数据）和
+// isFileFormat 标志；调用方据此选择 ipc.NewFileReader（需要 io.ReaderAt，通常要先
+// 整体读入内存或临时文件）还是 ipc.NewReader。
+func DetectReader(r io.Reader) (buffered *bufio.Reader, isFileFormat bool, err error) {
+	br := bufio.NewReaderSize(r, len(fileMagic)*2)
+
+	magic, err := br.Peek(len(fileMagic))
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, false, err
+	}
+
+	return br, bytes.Equal(magic, []byte(fileMagic)), nil
+}
@@ -0,0 +1,88 @@
+package arrowio
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDetectReader_FileMagic(t *testing.T) {
+	body := fileMagic + "rest of the arrow file payload"
+	br, isFile, err := DetectReader(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isFile {
+		t.Fatal("expected ARROW1 magic to be detected as File format")
+	}
+
+	data, err := io.ReadAll(br)
+	if err != nil {
+		t.Fatalf("unexpected error reading buffered reader: %v", err)
+	}
+	if string(data) != body {
+		t.Fatalf("Peek must not consume bytes from the stream, got %q", data)
+	}
+}
+
+func TestDetectReader_StreamBody(t *testing.T) {
+	body := "not an arrow file, just a stream of bytes"
+	_, isFile, err := DetectReader(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if isFile {
+		t.Fatal("expected non-ARROW1 body to not be detected as File format")
+	}
+}
+
+func TestDetectReader_ShortBody(t *testing.T) {
+	// Shorter than the magic itself; Peek hits EOF before filling the buffer.
+	_, isFile, err := DetectReader(bytes.NewReader([]byte("AR")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if isFile {
+		t.Fatal("expected a body shorter than the magic to not be detected as File format")
+	}
+}
+
+func TestCompressionOptions(t *testing.T) {
+	cases := []struct {
+		encoding string
+		wantNone bool
+	}{
+		{"zstd", false},
+		{"ZSTD", false},
+		{"lz4", false},
+		{"lz4_frame", false},
+		{"", true},
+		{"gzip", true},
+	}
+
+	for _, tc := range cases {
+		opts := compressionOptions(tc.encoding)
+		if tc.wantNone && len(opts) != 0 {
+			t.Errorf("encoding %q: expected no options, got %d", tc.encoding, len(opts))
+		}
+		if !tc.wantNone && len(opts) != 1 {
+			t.Errorf("encoding %q: expected exactly one option, got %d", tc.encoding, len(opts))
+		}
+	}
+}
+
+func TestIsFileContentType(t *testing.T) {
+	cases := map[string]bool{
+		"application/vnd.apache.arrow.file":    true,
+		"application/vnd.apache.arrow.feather": true,
+		"application/vnd.apache.arrow.stream":  false,
+		"application/json":                     false,
+	}
+
+	for contentType, want := range cases {
+		if got := isFileContentType(contentType); got != want {
+			t.Errorf("isFileContentType(%q) = %v, want %v", contentType, got, want)
+		}
+	}
+}
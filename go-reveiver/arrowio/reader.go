@@ -0,0 +1,144 @@
+package arrowio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/apache/arrow/go/v18/arrow"
+	"github.com/apache/arrow/go/v18/arrow/ipc"
+	"github.com/apache/arrow/go/v18/arrow/memory"
+)
+
+// BatchReader 统一了 ipc.Reader（Stream/Feather V2）和 ipc.FileReader（File 格式）的
+// 读取接口，让调用方不必关心底层是哪种物理编码。
+type BatchReader interface {
+	Schema() *arrow.Schema
+	Next() bool
+	Record() arrow.Record
+	Err() error
+	Release()
+}
+
+// OpenReader 根据 Content-Type 和 Content-Encoding 请求头，加上对 body 前几个字节的
+// 魔数探测，选出正确的 Arrow IPC reader：
+//   - application/vnd.apache.arrow.file 或探测到 "ARROW1" 魔数 -> File 格式（Feather V2
+//     与 Arrow File 共用同一套物理布局），body 会被整体读入内存以获得 io.ReaderAt
+//   - 其余情况 -> Stream 格式（application/vnd.apache.arrow.stream 的历史行为）
+//
+// Content-Encoding 为 lz4 或 zstd 时，会给 reader 加上对应的 body 解压选项，
+// 用于处理 IPC 消息体自带 LZ4_FRAME/ZSTD 压缩的情况。
+//
+// maxFileBytes 限制 File/Feather 路径整体读入内存的字节数上限（0 表示不限制）。
+// 这段读取发生在 quota.Manager 的 CheckedAllocator 之外（它只是一个普通的
+// []byte），所以没有这个上限的话，一个声明 Content-Type 为
+// application/vnd.apache.arrow.file 的客户端可以绕过 allocator 高水位线和
+// 字节限流，用一个无界的 body 把进程 OOM 掉。调用方应当传入与
+// quotaManager 高水位线一致的值。
+func OpenReader(body io.Reader, contentType, contentEncoding string, allocator memory.Allocator, maxFileBytes int64) (BatchReader, error) {
+	opts := []ipc.Option{ipc.WithAllocator(allocator)}
+	opts = append(opts, compressionOptions(contentEncoding)...)
+
+	br, looksLikeFile, err := DetectReader(body)
+	if err != nil {
+		return nil, fmt.Errorf("探测 Arrow 物理格式失败: %v", err)
+	}
+
+	wantsFile := isFileContentType(contentType) || looksLikeFile
+
+	if !wantsFile {
+		reader, err := ipc.NewReader(br, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("创建 Arrow Stream reader 失败: %v", err)
+		}
+		return reader, nil
+	}
+
+	// ipc.NewFileReader 需要 io.ReaderAt，Stream body 本身不可寻址，
+	// 所以把它整体读入内存后包一层 bytes.Reader，同时用 maxFileBytes 限制读取量。
+	var r io.Reader = br
+	if maxFileBytes > 0 {
+		r = io.LimitReader(br, maxFileBytes+1)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取 Arrow File 数据失败: %v", err)
+	}
+	if maxFileBytes > 0 && int64(len(data)) > maxFileBytes {
+		return nil, fmt.Errorf("Arrow File/Feather 请求体超过了允许的最大字节数 %d", maxFileBytes)
+	}
+
+	fileReader, err := ipc.NewFileReader(bytes.NewReader(data), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("创建 Arrow File reader 失败: %v", err)
+	}
+	return &fileReaderAdapter{reader: fileReader}, nil
+}
+
+// isFileContentType 识别显式声明了 File/Feather 编码的 Content-Type
+func isFileContentType(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mt = strings.TrimSpace(contentType)
+	}
+	switch mt {
+	case "application/vnd.apache.arrow.file", "application/vnd.apache.arrow.feather", "application/x-feather":
+		return true
+	default:
+		return false
+	}
+}
+
+// compressionOptions 把 Content-Encoding 映射成 ipc reader 的 body 解压选项
+func compressionOptions(contentEncoding string) []ipc.Option {
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "zstd":
+		return []ipc.Option{ipc.WithZstd()}
+	case "lz4", "lz4_frame":
+		return []ipc.Option{ipc.WithLZ4()}
+	default:
+		return nil
+	}
+}
+
+// fileReaderAdapter 让 *ipc.FileReader（按索引随机访问）满足 BatchReader
+// 的顺序流式接口（Next/Record/Err），与 ipc.Reader 的使用方式保持一致。
+type fileReaderAdapter struct {
+	reader  *ipc.FileReader
+	index   int
+	current arrow.Record
+	err     error
+}
+
+func (a *fileReaderAdapter) Schema() *arrow.Schema {
+	return a.reader.Schema()
+}
+
+func (a *fileReaderAdapter) Next() bool {
+	if a.index >= a.reader.NumRecords() {
+		return false
+	}
+	record, err := a.reader.Record(a.index)
+	if err != nil {
+		a.err = err
+		return false
+	}
+	a.current = record
+	a.index++
+	return true
+}
+
+func (a *fileReaderAdapter) Record() arrow.Record {
+	return a.current
+}
+
+func (a *fileReaderAdapter) Err() error {
+	return a.err
+}
+
+func (a *fileReaderAdapter) Release() {
+	// ipc.FileReader 自己管理底层缓冲区的生命周期，这里无需显式释放
+}
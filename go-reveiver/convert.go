@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liweiyuan/arrow-interop/go-reveiver/arrowconv"
+)
+
+// handleConvert 读取请求体中的 Arrow IPC stream，根据 Accept 头把它流式转换成
+// JSON、NDJSON 或 CSV 返回，使用 chunked transfer encoding 边读边写，不在内存里
+// 缓冲整个数据集。
+func handleConvert(c *gin.Context) {
+	defer c.Request.Body.Close()
+
+	format := arrowconv.NegotiateFormat(c.GetHeader("Accept"))
+
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", contentTypeFor(format))
+	c.Header("Transfer-Encoding", "chunked")
+
+	flusher, _ := c.Writer.(http.Flusher)
+
+	if err := arrowconv.Convert(c.Request.Body, c.Writer, format, flusher); err != nil {
+		log.Printf("转换 Arrow 流失败: %v", err)
+		// 响应可能已经部分写出，这里只能记录错误，无法再改写状态码
+		c.Writer.Write([]byte(fmt.Sprintf("\n转换失败: %v\n", err)))
+		return
+	}
+}
+
+// contentTypeFor 返回与 arrowconv.Format 对应的响应 Content-Type
+func contentTypeFor(format arrowconv.Format) string {
+	switch format {
+	case arrowconv.FormatJSON:
+		return "application/json"
+	case arrowconv.FormatCSV:
+		return "text/csv"
+	default:
+		return "application/x-ndjson"
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+
+	"github.com/apache/arrow/go/v18/arrow"
+	"github.com/apache/arrow/go/v18/arrow/flight"
+	"github.com/apache/arrow/go/v18/arrow/ipc"
+	"github.com/apache/arrow/go/v18/arrow/memory"
+	"google.golang.org/grpc"
+)
+
+// flightDataset 保存一个通过 DoPut 上传的数据集，供后续 DoGet 按 ticket 取回
+type flightDataset struct {
+	schema  *arrow.Schema
+	records []arrow.Record
+}
+
+// arrowFlightServer 实现 Apache Arrow Flight 的 FlightServiceServer 接口，
+// 与 Gin 的 /receive-arrow 处理器共享同一个 allocator 和 processBatch 流水线
+type arrowFlightServer struct {
+	flight.BaseFlightServer
+
+	allocator memory.Allocator
+
+	mu       sync.RWMutex
+	datasets map[string]*flightDataset
+}
+
+// newArrowFlightServer 创建一个空的 Flight 服务实例，复用调用方传入的 allocator
+func newArrowFlightServer(sharedAllocator memory.Allocator) *arrowFlightServer {
+	return &arrowFlightServer{
+		allocator: sharedAllocator,
+		datasets:  make(map[string]*flightDataset),
+	}
+}
+
+// releaseDataset 释放一个 flightDataset 持有的所有 record 引用
+func releaseDataset(ds *flightDataset) {
+	if ds == nil {
+		return
+	}
+	for _, r := range ds.records {
+		r.Release()
+	}
+}
+
+// DoPut 接收客户端推送的 record batch 流，写入同一个内存存储，
+// 并复用 processBatch 做打印/校验，保证与 HTTP 路径行为一致
+func (s *arrowFlightServer) DoPut(stream flight.FlightService_DoPutServer) error {
+	reader, err := flight.NewRecordReader(stream, ipc.WithAllocator(s.allocator))
+	if err != nil {
+		return fmt.Errorf("创建 Flight record reader 失败: %v", err)
+	}
+	defer reader.Release()
+
+	ticket := reader.LatestFlightDescriptor().GetPath()
+	key := flightKey(ticket)
+
+	batchCount := 0
+	var records []arrow.Record
+
+	for reader.Next() {
+		record := reader.Record()
+		record.Retain()
+
+		if err := processBatch(record, batchCount); err != nil {
+			log.Printf("警告：Flight DoPut 处理批次 %d 时发生错误: %v", batchCount+1, err)
+		}
+
+		records = append(records, record)
+		batchCount++
+
+		if err := stream.Send(&flight.PutResult{}); err != nil {
+			return err
+		}
+	}
+	if err := reader.Err(); err != nil {
+		return fmt.Errorf("读取 Flight 流时发生错误: %v", err)
+	}
+
+	s.mu.Lock()
+	old := s.datasets[key]
+	s.datasets[key] = &flightDataset{schema: reader.Schema(), records: records}
+	s.mu.Unlock()
+	releaseDataset(old)
+
+	log.Printf("Flight DoPut 完成 | ticket: %s, 批次数: %d", key, batchCount)
+	return nil
+}
+
+// DoGet 根据 ticket 查找已上传的数据集，把其中的 record batch 流式写回客户端
+func (s *arrowFlightServer) DoGet(tkt *flight.Ticket, stream flight.FlightService_DoGetServer) error {
+	key := string(tkt.GetTicket())
+
+	s.mu.RLock()
+	ds, ok := s.datasets[key]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("未找到 ticket 对应的数据集: %s", key)
+	}
+
+	writer := flight.NewRecordWriter(stream, ipc.WithSchema(ds.schema), ipc.WithAllocator(s.allocator))
+	defer writer.Close()
+
+	for _, record := range ds.records {
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("写入 Flight 响应流失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// GetSchema 返回指定数据集的 schema，供客户端在 DoGet 之前协商
+func (s *arrowFlightServer) GetSchema(_ context.Context, desc *flight.FlightDescriptor) (*flight.SchemaResult, error) {
+	key := flightKey(desc.GetPath())
+
+	s.mu.RLock()
+	ds, ok := s.datasets[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("未找到数据集: %s", key)
+	}
+
+	return &flight.SchemaResult{Schema: flight.SerializeSchema(ds.schema, s.allocator)}, nil
+}
+
+// ListFlights 列出当前所有可取回的数据集
+func (s *arrowFlightServer) ListFlights(_ *flight.Criteria, stream flight.FlightService_ListFlightsServer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for key, ds := range s.datasets {
+		rows := int64(0)
+		for _, r := range ds.records {
+			rows += r.NumRows()
+		}
+
+		info := &flight.FlightInfo{
+			Schema:           flight.SerializeSchema(ds.schema, s.allocator),
+			FlightDescriptor: &flight.FlightDescriptor{Type: flight.DescriptorPATH, Path: []string{key}},
+			Endpoint: []*flight.FlightEndpoint{
+				{Ticket: &flight.Ticket{Ticket: []byte(key)}},
+			},
+			TotalRecords: rows,
+			TotalBytes:   -1,
+		}
+		if err := stream.Send(info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flightKey 把 FlightDescriptor 的 path 段拼接成数据集存储的 key
+func flightKey(path []string) string {
+	if len(path) == 0 {
+		return "default"
+	}
+	key := path[0]
+	for _, p := range path[1:] {
+		key += "/" + p
+	}
+	return key
+}
+
+// startFlightServer 在给定端口上启动 Arrow Flight gRPC 服务，阻塞运行
+func startFlightServer(port int, sharedAllocator memory.Allocator) error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return fmt.Errorf("监听 Flight 端口失败: %v", err)
+	}
+
+	grpcServer := grpc.NewServer()
+	flight.RegisterFlightServiceServer(grpcServer, newArrowFlightServer(sharedAllocator))
+
+	log.Printf("Arrow Flight gRPC server listening on port %d...\n", port)
+	return grpcServer.Serve(lis)
+}
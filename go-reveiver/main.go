@@ -4,21 +4,29 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"strings"
 
 	"github.com/apache/arrow/go/v18/arrow"
 	"github.com/apache/arrow/go/v18/arrow/array"
-	"github.com/apache/arrow/go/v18/arrow/ipc"
 	"github.com/apache/arrow/go/v18/arrow/memory"
 	"github.com/gin-gonic/gin"
+	"github.com/liweiyuan/arrow-interop/go-reveiver/arrowio"
+	"github.com/liweiyuan/arrow-interop/go-reveiver/quota"
+	"github.com/liweiyuan/arrow-interop/go-reveiver/sink"
 )
 
-// 创建一个全局的内存分配器
-var allocator *memory.GoAllocator
+const (
+	allocatorMaxBytes   = 512 * 1024 * 1024 // 512MiB 高水位线，触顶后中间件拒绝新的请求（已开始的分配不受限制）
+	requestsPerSecLimit = 20.0              // 每个远端 IP 每秒允许的请求数
+	bytesPerSecLimit    = 64 * 1024 * 1024  // 每个数据集每秒允许的字节数
+)
 
-func init() {
-	allocator = memory.NewGoAllocator()
-}
+// quotaManager 包装全局分配器，统一做内存高水位线和限流背压
+var quotaManager = quota.NewManager(memory.NewGoAllocator(), allocatorMaxBytes, requestsPerSecLimit, bytesPerSecLimit)
+
+// allocator 是所有 Arrow reader/writer 共享的分配器，现在经由 quotaManager 做配额记账
+var allocator memory.Allocator = quotaManager.Allocator()
 
 func main() {
 	r := gin.Default()
@@ -28,7 +36,18 @@ func main() {
 			"message": "Go Arrow receiver is running!",
 		})
 	})
-	r.POST("/receive-arrow", handleArrowData)
+	r.POST("/receive-arrow", rateLimitMiddleware(), handleArrowData)
+	r.POST("/convert", handleConvert)
+	r.GET("/schemas/:dataset", handleGetSchema)
+	r.POST("/schemas/:dataset", handleRegisterSchema)
+	r.GET("/metrics", handleMetrics())
+
+	flightPort := 8815
+	go func() {
+		if err := startFlightServer(flightPort, allocator); err != nil {
+			log.Fatalf("Failed to start Flight server: %v", err)
+		}
+	}()
 
 	port := 8080
 	log.Printf("Go Arrow receiver listening on port %d...\n", port)
@@ -53,15 +72,15 @@ func formatValue(v interface{}) string {
 // handleArrowData 处理接收到的 Arrow 数据
 func handleArrowData(c *gin.Context) {
 	contentType := c.Request.Header.Get("Content-Type")
-	log.Printf("接收到请求 | Content-Type: %s", contentType)
+	contentEncoding := c.Request.Header.Get("Content-Encoding")
+	log.Printf("接收到请求 | Content-Type: %s, Content-Encoding: %s", contentType, contentEncoding)
 
-	if contentType != "application/vnd.apache.arrow.stream" {
-		handleError(c, fmt.Errorf("不支持的 Content-Type: %s，需要 application/vnd.apache.arrow.stream", contentType))
-		return
+	if c.Request.ContentLength > 0 {
+		bytesInTotal.Add(float64(c.Request.ContentLength))
 	}
 
-	// 使用自定义内存分配器创建 reader
-	reader, err := ipc.NewReader(c.Request.Body, ipc.WithAllocator(allocator))
+	// 根据 Content-Type/Content-Encoding 和魔数探测选择 Stream/File reader 及解压方式
+	reader, err := arrowio.OpenReader(c.Request.Body, contentType, contentEncoding, allocator, int64(quotaManager.MaxBytes()))
 	if err != nil {
 		handleError(c, fmt.Errorf("创建 Arrow IPC reader 失败: %v", err))
 		c.Request.Body.Close()
@@ -73,6 +92,29 @@ func handleArrowData(c *gin.Context) {
 	schema := reader.Schema()
 	log.Printf("接收到 Arrow Schema:\n%s", schema)
 
+	dataset := c.Request.Header.Get(datasetHeader)
+	if dataset != "" {
+		upcast, err := registry.Validate(c.Request.Context(), dataset, schema)
+		if err != nil {
+			handleError(c, fmt.Errorf("schema 校验未通过: %v", err))
+			return
+		}
+		if upcast != nil {
+			log.Printf("数据集 %q 的 schema 已自动 upcast 为:\n%s", dataset, upcast)
+		}
+	}
+
+	recordSink, err := newSinkFromRequest(c, schema)
+	if err != nil {
+		handleError(c, fmt.Errorf("初始化 sink 失败: %v", err))
+		return
+	}
+	defer func() {
+		if err := recordSink.Close(); err != nil {
+			log.Printf("警告：关闭 sink 时发生错误: %v", err)
+		}
+	}()
+
 	rowCountTotal := 0
 	batchCount := 0
 
@@ -92,8 +134,15 @@ func handleArrowData(c *gin.Context) {
 				return
 			}
 
+			if err := recordSink.Write(record); err != nil {
+				log.Printf("警告：sink 写入批次 %d 时发生错误: %v", batchCount+1, err)
+				return
+			}
+
 			rowCountTotal += int(record.NumRows())
 			batchCount++
+			batchesTotal.Inc()
+			rowsTotal.Add(float64(record.NumRows()))
 		}()
 
 		if err := reader.Err(); err != nil {
@@ -266,6 +315,30 @@ func safeGetOffsets(arr arrow.Array, rowIndex int) (start, end int64, ok bool) {
 	}
 }
 
+// newSinkFromRequest 根据 X-Arrow-Sink 请求头选择 RecordSink 实现，
+// 连接参数目前取自环境变量，留作后续接入配置文件/flag 的扩展点
+func newSinkFromRequest(c *gin.Context, schema *arrow.Schema) (sink.RecordSink, error) {
+	kind := sink.Kind(c.Request.Header.Get(sink.HeaderName))
+
+	cfg := sink.Config{
+		ParquetDir:   envOr("ARROW_SINK_PARQUET_DIR", "parquet-out"),
+		DuckDBPath:   envOr("ARROW_SINK_DUCKDB_PATH", ":memory:"),
+		DuckDBTable:  envOr("ARROW_SINK_DUCKDB_TABLE", "arrow_batches"),
+		KafkaBrokers: strings.Split(envOr("ARROW_SINK_KAFKA_BROKERS", "localhost:9092"), ","),
+		KafkaTopic:   envOr("ARROW_SINK_KAFKA_TOPIC", "arrow-batches"),
+	}
+
+	return sink.New(kind, schema, cfg)
+}
+
+// envOr 返回环境变量的值，未设置时回退到 fallback
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
 // handleError 统一处理错误响应
 func handleError(c *gin.Context, err error) {
 	log.Printf("错误: %v", err)
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// 暴露给 /metrics 的 Prometheus 指标：吞吐、限流拒绝和 allocator 高水位线情况，
+// 接收端此前完全没有可观测性，无法判断是否正在被一个无界的客户端流压垮。
+var (
+	bytesInTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "arrow_receiver_bytes_in_total",
+		Help: "接收到的 Arrow IPC 流字节总数（按请求声明的 Content-Length 累计，未声明长度的请求不计入）",
+	})
+	batchesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "arrow_receiver_batches_total",
+		Help: "成功处理的 record batch 总数",
+	})
+	rowsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "arrow_receiver_rows_total",
+		Help: "成功处理的行总数",
+	})
+	rejectionsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "arrow_receiver_rejections_total",
+		Help: "因限流或 allocator 高水位线被拒绝的请求总数",
+	})
+	allocatorBytesInUse = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "arrow_receiver_allocator_bytes_in_use",
+		Help: "CheckedAllocator 当前记录的已分配字节数",
+	}, func() float64 {
+		return float64(quotaManager.BytesInUse())
+	})
+)
+
+// handleMetrics 以 Prometheus 文本格式暴露上面的指标
+func handleMetrics() gin.HandlerFunc {
+	handler := promhttp.Handler()
+	return gin.WrapH(handler)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitMiddleware 在 ipc.NewReader 创建之前拦截请求：按远端 IP 做请求级限流，
+// 按数据集（X-Arrow-Dataset，缺省时退化为 IP）结合 Content-Length 做字节级限流，
+// 并检查 allocator 高水位线。任一项超额都返回 429 和 Retry-After，中止请求体读取。
+func rateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+
+		if ok, retryAfter := quotaManager.AllowRequest(ip); !ok {
+			rejectionsTotal.Inc()
+			c.Header("Retry-After", fmt.Sprintf("%.0f", retryAfter.Seconds()))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "请求频率超出限制"})
+			return
+		}
+
+		dataset := c.Request.Header.Get(datasetHeader)
+		if dataset == "" {
+			dataset = ip
+		}
+
+		// Content-Length 未知（例如 chunked 请求）时跳过字节级限流，只依赖 allocator 高水位线
+		n := 0
+		if c.Request.ContentLength > 0 {
+			n = int(c.Request.ContentLength)
+		}
+		ok, retryAfter, err := quotaManager.AllowBytes(dataset, n)
+		if err != nil || !ok {
+			rejectionsTotal.Inc()
+			c.Header("Retry-After", fmt.Sprintf("%.0f", retryAfter.Seconds()))
+			msg := "字节吞吐量超出限制"
+			if err != nil {
+				msg = err.Error()
+			}
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": msg})
+			return
+		}
+
+		c.Next()
+	}
+}
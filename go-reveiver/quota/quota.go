@@ -0,0 +1,110 @@
+// Package quota 提供接收端的背压控制：一个带高水位线的 CheckedAllocator 包装，
+// 以及按远端 IP 和按数据集的令牌桶限流器，在 ipc.NewReader 创建之前拒绝超额请求。
+package quota
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow/go/v18/arrow/memory"
+	"golang.org/x/time/rate"
+)
+
+// Manager 包装一个 memory.CheckedAllocator 记录字节使用量，AllowBytes 在使用量加上
+// 新请求的字节数超过 maxBytes 时拒绝该请求；allocator 本身的分配不受 maxBytes 限制。
+// Manager 还按 key（远端 IP 或数据集名）维护独立的令牌桶限流器。
+type Manager struct {
+	allocator *memory.CheckedAllocator
+	maxBytes  uint64
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+
+	requestsPerSec float64
+	bytesPerSec    float64
+	burst          int
+}
+
+// NewManager 用 base 分配器构造一个带配额的 Manager。
+// requestsPerSec/bytesPerSec 是每个 key（IP 或数据集）的限流速率，maxBytes 是
+// allocator 的全局高水位线，由 AllowBytes 在请求开始前检查；超过时 AllowBytes 返回错误，
+// 已经发出的分配不会因此失败。
+func NewManager(base memory.Allocator, maxBytes uint64, requestsPerSec, bytesPerSec float64) *Manager {
+	return &Manager{
+		allocator:      memory.NewCheckedAllocator(base),
+		maxBytes:       maxBytes,
+		limiters:       make(map[string]*rate.Limiter),
+		requestsPerSec: requestsPerSec,
+		bytesPerSec:    bytesPerSec,
+		burst:          int(bytesPerSec),
+	}
+}
+
+// Allocator 返回底层的 CheckedAllocator，供 ipc.NewReader(ipc.WithAllocator(...)) 使用
+func (m *Manager) Allocator() *memory.CheckedAllocator {
+	return m.allocator
+}
+
+// BytesInUse 返回 allocator 当前记录的已分配字节数（高水位线指标来源）
+func (m *Manager) BytesInUse() int64 {
+	return int64(m.allocator.CurrentAlloc())
+}
+
+// MaxBytes 返回 allocator 的高水位线上限，供需要在分配前就预估大小的调用方
+// （例如把整个请求体读入内存的 Arrow File reader）做自己的读取上限控制
+func (m *Manager) MaxBytes() uint64 {
+	return m.maxBytes
+}
+
+// AllowRequest 检查 key（通常是远端 IP）是否还有请求配额，ok 为 false 时调用方应
+// 返回 429 并附带 Retry-After
+func (m *Manager) AllowRequest(key string) (ok bool, retryAfter time.Duration) {
+	limiter := m.requestLimiterFor(key)
+	if limiter.Allow() {
+		return true, 0
+	}
+	reservation := limiter.Reserve()
+	delay := reservation.Delay()
+	reservation.Cancel()
+	return false, delay
+}
+
+// AllowBytes 检查 key（通常是数据集名）是否还有字节配额，并且 allocator 尚未触顶
+func (m *Manager) AllowBytes(key string, n int) (ok bool, retryAfter time.Duration, err error) {
+	if m.maxBytes > 0 && uint64(int64(m.allocator.CurrentAlloc()))+uint64(n) > m.maxBytes {
+		return false, time.Second, fmt.Errorf("allocator 高水位线已触顶: 当前 %d 字节，上限 %d 字节", int64(m.allocator.CurrentAlloc()), m.maxBytes)
+	}
+
+	limiter := m.byteLimiterFor(key)
+	if limiter.AllowN(time.Now(), n) {
+		return true, 0, nil
+	}
+	reservation := limiter.ReserveN(time.Now(), n)
+	delay := reservation.Delay()
+	reservation.Cancel()
+	return false, delay, nil
+}
+
+func (m *Manager) requestLimiterFor(key string) *rate.Limiter {
+	return m.limiterFor("req:"+key, rate.Limit(m.requestsPerSec), int(m.requestsPerSec))
+}
+
+func (m *Manager) byteLimiterFor(key string) *rate.Limiter {
+	return m.limiterFor("bytes:"+key, rate.Limit(m.bytesPerSec), m.burst)
+}
+
+func (m *Manager) limiterFor(key string, limit rate.Limit, burst int) *rate.Limiter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	limiter, ok := m.limiters[key]
+	if !ok {
+		if burst <= 0 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(limit, burst)
+		m.limiters[key] = limiter
+	}
+	return limiter
+}
@@ -0,0 +1,60 @@
+package quota
+
+import (
+	"testing"
+
+	"github.com/apache/arrow/go/v18/arrow/memory"
+)
+
+func TestAllowRequest_TokenBucketLimitsBurst(t *testing.T) {
+	m := NewManager(memory.NewGoAllocator(), 0, 1, 1)
+
+	allowed := 0
+	for i := 0; i < 5; i++ {
+		if ok, _ := m.AllowRequest("1.2.3.4"); ok {
+			allowed++
+		}
+	}
+
+	if allowed == 0 {
+		t.Fatal("expected at least the initial burst to be allowed")
+	}
+	if allowed >= 5 {
+		t.Fatalf("expected the token bucket to reject some requests, allowed all %d", allowed)
+	}
+}
+
+func TestAllowRequest_SeparateKeysHaveIndependentBuckets(t *testing.T) {
+	m := NewManager(memory.NewGoAllocator(), 0, 1, 1)
+
+	if ok, _ := m.AllowRequest("ip-a"); !ok {
+		t.Fatal("expected first request from ip-a to be allowed")
+	}
+	if ok, _ := m.AllowRequest("ip-b"); !ok {
+		t.Fatal("expected first request from a different key (ip-b) to be allowed independently")
+	}
+}
+
+func TestAllowBytes_RejectsOverAllocatorHighWaterMark(t *testing.T) {
+	m := NewManager(memory.NewGoAllocator(), 100, 1000, 1000)
+
+	ok, _, err := m.AllowBytes("dataset-a", 200)
+	if err == nil {
+		t.Fatal("expected allocating more bytes than maxBytes to be rejected")
+	}
+	if ok {
+		t.Fatal("expected ok=false when allocator high-water mark is exceeded")
+	}
+}
+
+func TestAllowBytes_WithinLimitsSucceeds(t *testing.T) {
+	m := NewManager(memory.NewGoAllocator(), 0, 1000, 1000)
+
+	ok, _, err := m.AllowBytes("dataset-a", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected a small request well within quota to be allowed")
+	}
+}
@@ -0,0 +1,126 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/arrow/go/v18/arrow"
+	"github.com/apache/arrow/go/v18/arrow/flight"
+	"github.com/apache/arrow/go/v18/arrow/memory"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisRegistry 是 Registry 的 Redis 支持版本，用 Redis hash 把 schema 注册表
+// 存在共享存储里，这样部署多个副本的接收端时，它们能看到同一份当前 schema。
+// 校验逻辑和 Registry 完全一致，只是把 schema 的读写换成了 Redis 往返。
+type RedisRegistry struct {
+	client    *redis.Client
+	hash      string
+	allocator memory.Allocator
+}
+
+// redisEntry 是写入 Redis 的序列化形式：schema 以 Arrow Flight 的 schema 序列化格式编码存储
+type redisEntry struct {
+	ID            string        `json:"id"`
+	Compatibility Compatibility `json:"compatibility"`
+	SchemaBytes   []byte        `json:"schema_bytes"`
+}
+
+// NewRedisRegistry 连接到 addr 处的 Redis，把所有数据集的 schema 记录存在 hashKey 这一个 hash 里
+func NewRedisRegistry(addr, hashKey string, allocator memory.Allocator) *RedisRegistry {
+	return &RedisRegistry{
+		client:    redis.NewClient(&redis.Options{Addr: addr}),
+		hash:      hashKey,
+		allocator: allocator,
+	}
+}
+
+// Register 为 dataset 注册（或更新）一个 schema 和兼容性策略，返回分配的 schema ID
+func (r *RedisRegistry) Register(ctx context.Context, dataset string, schema *arrow.Schema, compat Compatibility) (string, error) {
+	if compat == "" {
+		compat = CompatBackward
+	}
+
+	id, err := r.put(ctx, dataset, schema, compat)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Get 返回 dataset 当前注册的 schema，如果尚未注册则 ok 为 false
+func (r *RedisRegistry) Get(ctx context.Context, dataset string) (*Entry, bool, error) {
+	payload, err := r.client.HGet(ctx, r.hash, dataset).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("读取 Redis 失败: %v", err)
+	}
+
+	entry, err := r.decode(payload)
+	if err != nil {
+		return nil, false, err
+	}
+	return entry, true, nil
+}
+
+// Validate 校验逻辑与 Registry.Validate 相同，只是底层存储换成了 Redis：
+// 兼容但不同的 schema（数值类型放宽等）会被当作新的当前版本写回 Redis。
+func (r *RedisRegistry) Validate(ctx context.Context, dataset string, incoming *arrow.Schema) (*arrow.Schema, error) {
+	existing, ok, err := r.Get(ctx, dataset)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if _, err := r.put(ctx, dataset, incoming, CompatBackward); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	if existing.Compatibility == CompatNone || existing.Schema.Equal(incoming) {
+		return nil, nil
+	}
+
+	upcast, err := checkCompatibility(existing.Schema, incoming, existing.Compatibility)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.put(ctx, dataset, upcast, existing.Compatibility); err != nil {
+		return nil, err
+	}
+	return upcast, nil
+}
+
+// put 序列化 schema 并写入 dataset 对应的 Redis hash 字段，返回分配的 schema ID
+func (r *RedisRegistry) put(ctx context.Context, dataset string, schema *arrow.Schema, compat Compatibility) (string, error) {
+	schemaBytes := flight.SerializeSchema(schema, r.allocator)
+
+	entry := redisEntry{ID: Fingerprint(schema), Compatibility: compat, SchemaBytes: schemaBytes}
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return "", fmt.Errorf("序列化 schema 注册项失败: %v", err)
+	}
+
+	if err := r.client.HSet(ctx, r.hash, dataset, payload).Err(); err != nil {
+		return "", fmt.Errorf("写入 Redis 失败: %v", err)
+	}
+	return entry.ID, nil
+}
+
+func (r *RedisRegistry) decode(payload []byte) (*Entry, error) {
+	var stored redisEntry
+	if err := json.Unmarshal(payload, &stored); err != nil {
+		return nil, fmt.Errorf("反序列化 schema 注册项失败: %v", err)
+	}
+
+	schema, err := flight.DeserializeSchema(stored.SchemaBytes, r.allocator)
+	if err != nil {
+		return nil, fmt.Errorf("还原 schema 失败: %v", err)
+	}
+
+	return &Entry{ID: stored.ID, Schema: schema, Compatibility: stored.Compatibility}, nil
+}
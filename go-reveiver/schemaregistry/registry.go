@@ -0,0 +1,218 @@
+// Package schemaregistry 按数据集（由 X-Arrow-Dataset 请求头标识）记录当前生效的 Arrow
+// schema（不保留旧版本），对新到达的 schema 做兼容性校验，防止不兼容的变更被静默接受。
+package schemaregistry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/apache/arrow/go/v18/arrow"
+)
+
+// Store 是 Registry（进程内）和 RedisRegistry（共享存储）共同实现的接口，
+// 让 /receive-arrow 和 /schemas 不需要关心当前选用的是哪种后端。
+type Store interface {
+	Register(ctx context.Context, dataset string, schema *arrow.Schema, compat Compatibility) (string, error)
+	Get(ctx context.Context, dataset string) (*Entry, bool, error)
+	Validate(ctx context.Context, dataset string, incoming *arrow.Schema) (*arrow.Schema, error)
+}
+
+// Compatibility 是数据集注册时声明的兼容性策略
+type Compatibility string
+
+const (
+	// CompatBackward 新 schema 必须能读旧数据：只允许新增可空字段、移除字段、放宽约束
+	CompatBackward Compatibility = "BACKWARD"
+	// CompatForward 旧 reader 必须能读新数据：允许新增字段（reader 忽略）和移除可空字段，
+	// 不允许移除非空字段；同名字段的类型规则与 BACKWARD 相同（相同类型、数值放宽、dictionary 索引增长），类型收窄被拒绝
+	CompatForward Compatibility = "FORWARD"
+	// CompatFull 同时满足 BACKWARD 和 FORWARD
+	CompatFull Compatibility = "FULL"
+	// CompatNone 不做任何兼容性校验，等价于历史上的“静默接受任何 schema”行为
+	CompatNone Compatibility = "NONE"
+)
+
+// Entry 是某个数据集在注册表里的一条记录：当前生效 schema 及其指纹 ID
+type Entry struct {
+	ID            string
+	Schema        *arrow.Schema
+	Compatibility Compatibility
+}
+
+// Registry 按数据集名维护当前生效的 schema（新版本覆盖旧版本），并发安全
+type Registry struct {
+	mu       sync.RWMutex
+	datasets map[string]*Entry
+}
+
+// New 创建一个空的进程内 registry
+func New() *Registry {
+	return &Registry{datasets: make(map[string]*Entry)}
+}
+
+var (
+	_ Store = (*Registry)(nil)
+	_ Store = (*RedisRegistry)(nil)
+)
+
+// Register 为 dataset 注册（或更新）一个 schema 和兼容性策略，返回分配的 schema ID
+func (r *Registry) Register(_ context.Context, dataset string, schema *arrow.Schema, compat Compatibility) (string, error) {
+	if compat == "" {
+		compat = CompatBackward
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := Fingerprint(schema)
+	r.datasets[dataset] = &Entry{ID: id, Schema: schema, Compatibility: compat}
+	return id, nil
+}
+
+// Get 返回 dataset 当前注册的 schema，如果尚未注册则 ok 为 false
+func (r *Registry) Get(_ context.Context, dataset string) (*Entry, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.datasets[dataset]
+	return e, ok, nil
+}
+
+// List 返回所有已注册的数据集名
+func (r *Registry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.datasets))
+	for name := range r.datasets {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Validate 校验 incoming schema 是否与 dataset 当前注册的 schema 兼容。
+// 如果 dataset 还没有注册过任何 schema，incoming 会被直接接受并注册为初始版本。
+// 当 incoming 是一次兼容但不同的变更（数值类型放宽、dictionary 索引增长等），
+// Validate 会把它记为 dataset 新的当前 schema（即“自动 upcast”），并把新 schema
+// 作为 upcast 返回，调用方之后的批次都应按这个新 schema 处理。
+func (r *Registry) Validate(_ context.Context, dataset string, incoming *arrow.Schema) (upcast *arrow.Schema, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.datasets[dataset]
+	if !ok {
+		r.datasets[dataset] = &Entry{ID: Fingerprint(incoming), Schema: incoming, Compatibility: CompatBackward}
+		return nil, nil
+	}
+
+	if existing.Compatibility == CompatNone || existing.Schema.Equal(incoming) {
+		return nil, nil
+	}
+
+	upcast, err = checkCompatibility(existing.Schema, incoming, existing.Compatibility)
+	if err != nil {
+		return nil, err
+	}
+
+	existing.ID = Fingerprint(upcast)
+	existing.Schema = upcast
+	return upcast, nil
+}
+
+// Fingerprint 计算 schema 的稳定指纹，作为其注册 ID
+func Fingerprint(schema *arrow.Schema) string {
+	h := sha256.Sum256([]byte(schema.String()))
+	return hex.EncodeToString(h[:])
+}
+
+// checkCompatibility 实现 BACKWARD/FORWARD/FULL 规则：同名字段允许相同类型、数值类型
+// 放宽（widening）和 dictionary 索引类型增长；BACKWARD 拒绝新增非空字段（移除任何字段都允许），
+// FORWARD 拒绝移除非空字段（新增任何字段都允许），FULL 同时套用两者；其余差异一律拒绝。
+func checkCompatibility(oldSchema, newSchema *arrow.Schema, compat Compatibility) (*arrow.Schema, error) {
+	oldFields := fieldsByName(oldSchema)
+	newFields := fieldsByName(newSchema)
+
+	// BACKWARD：新 schema 必须能读旧数据，所以新 schema 不能新增旧数据里没有值的非空字段
+	if compat == CompatBackward || compat == CompatFull {
+		for name, nf := range newFields {
+			of, ok := oldFields[name]
+			if !ok {
+				if !nf.Nullable {
+					return nil, fmt.Errorf("BACKWARD 不兼容：新增了非空字段 %q", name)
+				}
+				continue
+			}
+			if !fieldCompatible(of, nf) {
+				return nil, fmt.Errorf("BACKWARD 不兼容：字段 %q 类型从 %s 变为 %s", name, of.Type, nf.Type)
+			}
+		}
+	}
+
+	// FORWARD：旧 reader 必须能读新数据，所以新 schema 不能移除旧 reader 依赖的非空字段
+	if compat == CompatForward || compat == CompatFull {
+		for name, of := range oldFields {
+			nf, ok := newFields[name]
+			if !ok {
+				if !of.Nullable {
+					return nil, fmt.Errorf("FORWARD 不兼容：移除了非空字段 %q", name)
+				}
+				continue
+			}
+			if !fieldCompatible(of, nf) {
+				return nil, fmt.Errorf("FORWARD 不兼容：字段 %q 类型从 %s 变为 %s", name, of.Type, nf.Type)
+			}
+		}
+	}
+
+	return newSchema, nil
+}
+
+func fieldsByName(schema *arrow.Schema) map[string]arrow.Field {
+	out := make(map[string]arrow.Field, schema.NumFields())
+	for _, f := range schema.Fields() {
+		out[f.Name] = f
+	}
+	return out
+}
+
+// fieldCompatible 允许相同类型，或数值类型的宽化（如 int32 -> int64，float32 -> float64），
+// 以及 dictionary 索引类型的增长（如 int8 索引 -> int32 索引，值类型不变）
+func fieldCompatible(oldField, newField arrow.Field) bool {
+	if arrow.TypeEqual(oldField.Type, newField.Type) {
+		return true
+	}
+
+	if isWidening(oldField.Type, newField.Type) {
+		return true
+	}
+
+	oldDict, oldOK := oldField.Type.(*arrow.DictionaryType)
+	newDict, newOK := newField.Type.(*arrow.DictionaryType)
+	if oldOK && newOK && arrow.TypeEqual(oldDict.ValueType, newDict.ValueType) {
+		return isWidening(oldDict.IndexType, newDict.IndexType) || arrow.TypeEqual(oldDict.IndexType, newDict.IndexType)
+	}
+
+	return false
+}
+
+// widenings 枚举了被认为是“安全放宽”的数值类型升级路径
+var widenings = map[arrow.Type][]arrow.Type{
+	arrow.INT8:    {arrow.INT16, arrow.INT32, arrow.INT64},
+	arrow.INT16:   {arrow.INT32, arrow.INT64},
+	arrow.INT32:   {arrow.INT64},
+	arrow.UINT8:   {arrow.UINT16, arrow.UINT32, arrow.UINT64},
+	arrow.UINT16:  {arrow.UINT32, arrow.UINT64},
+	arrow.UINT32:  {arrow.UINT64},
+	arrow.FLOAT32: {arrow.FLOAT64},
+}
+
+func isWidening(from, to arrow.DataType) bool {
+	for _, candidate := range widenings[from.ID()] {
+		if candidate == to.ID() {
+			return true
+		}
+	}
+	return false
+}
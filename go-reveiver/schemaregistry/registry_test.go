@@ -0,0 +1,132 @@
+package schemaregistry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow/go/v18/arrow"
+)
+
+func schema(fields ...arrow.Field) *arrow.Schema {
+	return arrow.NewSchema(fields, nil)
+}
+
+func TestCheckCompatibility_BackwardAllowsNewNullableField(t *testing.T) {
+	oldSchema := schema(arrow.Field{Name: "id", Type: arrow.PrimitiveTypes.Int32})
+	newSchema := schema(
+		arrow.Field{Name: "id", Type: arrow.PrimitiveTypes.Int32},
+		arrow.Field{Name: "extra", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+	)
+
+	if _, err := checkCompatibility(oldSchema, newSchema, CompatBackward); err != nil {
+		t.Fatalf("expected backward-compatible schema to pass, got: %v", err)
+	}
+}
+
+func TestCheckCompatibility_BackwardAllowsRemovedNonNullableField(t *testing.T) {
+	// BACKWARD: new schema must be able to read old data, so dropping a field
+	// the new schema no longer cares about is fine regardless of its old nullability.
+	oldSchema := schema(
+		arrow.Field{Name: "id", Type: arrow.PrimitiveTypes.Int32, Nullable: false},
+		arrow.Field{Name: "name", Type: arrow.BinaryTypes.String},
+	)
+	newSchema := schema(arrow.Field{Name: "name", Type: arrow.BinaryTypes.String})
+
+	if _, err := checkCompatibility(oldSchema, newSchema, CompatBackward); err != nil {
+		t.Fatalf("expected removing a field to be allowed under BACKWARD, got: %v", err)
+	}
+}
+
+func TestCheckCompatibility_BackwardRejectsNewNonNullableField(t *testing.T) {
+	// BACKWARD: new schema reading old data would find no value for a field
+	// the old data never wrote, so a new non-nullable field is not allowed.
+	oldSchema := schema(arrow.Field{Name: "id", Type: arrow.PrimitiveTypes.Int32})
+	newSchema := schema(
+		arrow.Field{Name: "id", Type: arrow.PrimitiveTypes.Int32},
+		arrow.Field{Name: "extra", Type: arrow.PrimitiveTypes.Int32, Nullable: false},
+	)
+
+	if _, err := checkCompatibility(oldSchema, newSchema, CompatBackward); err == nil {
+		t.Fatal("expected adding a non-nullable field to be rejected under BACKWARD")
+	}
+}
+
+func TestCheckCompatibility_ForwardAllowsNewNonNullableField(t *testing.T) {
+	// FORWARD: an old reader simply ignores a field it doesn't know about.
+	oldSchema := schema(arrow.Field{Name: "id", Type: arrow.PrimitiveTypes.Int32})
+	newSchema := schema(
+		arrow.Field{Name: "id", Type: arrow.PrimitiveTypes.Int32},
+		arrow.Field{Name: "extra", Type: arrow.PrimitiveTypes.Int32, Nullable: false},
+	)
+
+	if _, err := checkCompatibility(oldSchema, newSchema, CompatForward); err != nil {
+		t.Fatalf("expected adding a non-nullable field to be allowed under FORWARD, got: %v", err)
+	}
+}
+
+func TestCheckCompatibility_ForwardRejectsRemovedNonNullableField(t *testing.T) {
+	// FORWARD: an old reader expects that non-nullable field to be present in new data.
+	oldSchema := schema(
+		arrow.Field{Name: "id", Type: arrow.PrimitiveTypes.Int32, Nullable: false},
+		arrow.Field{Name: "name", Type: arrow.BinaryTypes.String},
+	)
+	newSchema := schema(arrow.Field{Name: "name", Type: arrow.BinaryTypes.String})
+
+	if _, err := checkCompatibility(oldSchema, newSchema, CompatForward); err == nil {
+		t.Fatal("expected removing a non-nullable field to be rejected under FORWARD")
+	}
+}
+
+func TestFieldCompatible_NumericWidening(t *testing.T) {
+	of := arrow.Field{Name: "v", Type: arrow.PrimitiveTypes.Int32}
+	nf := arrow.Field{Name: "v", Type: arrow.PrimitiveTypes.Int64}
+
+	if !fieldCompatible(of, nf) {
+		t.Fatal("expected int32 -> int64 to be considered a compatible widening")
+	}
+}
+
+func TestFieldCompatible_NumericNarrowingRejected(t *testing.T) {
+	of := arrow.Field{Name: "v", Type: arrow.PrimitiveTypes.Int64}
+	nf := arrow.Field{Name: "v", Type: arrow.PrimitiveTypes.Int32}
+
+	if fieldCompatible(of, nf) {
+		t.Fatal("expected int64 -> int32 narrowing to be rejected")
+	}
+}
+
+func TestFieldCompatible_IncompatibleTypeChangeRejected(t *testing.T) {
+	of := arrow.Field{Name: "v", Type: arrow.PrimitiveTypes.Int32}
+	nf := arrow.Field{Name: "v", Type: arrow.BinaryTypes.String}
+
+	if fieldCompatible(of, nf) {
+		t.Fatal("expected int32 -> string to be rejected")
+	}
+}
+
+func TestRegistry_ValidateAppliesUpcast(t *testing.T) {
+	r := New()
+	oldSchema := schema(arrow.Field{Name: "v", Type: arrow.PrimitiveTypes.Int32})
+	newSchema := schema(arrow.Field{Name: "v", Type: arrow.PrimitiveTypes.Int64})
+
+	ctx := context.Background()
+	if _, err := r.Register(ctx, "ds", oldSchema, CompatBackward); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+
+	upcast, err := r.Validate(ctx, "ds", newSchema)
+	if err != nil {
+		t.Fatalf("expected widening to validate, got: %v", err)
+	}
+	if upcast == nil {
+		t.Fatal("expected a non-nil upcast schema")
+	}
+
+	entry, ok, err := r.Get(ctx, "ds")
+	if err != nil || !ok {
+		t.Fatalf("expected dataset to be registered, err=%v ok=%v", err, ok)
+	}
+	if !entry.Schema.Equal(newSchema) {
+		t.Fatalf("expected registry to persist the upcast schema, got: %s", entry.Schema)
+	}
+}
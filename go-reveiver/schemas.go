@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/apache/arrow/go/v18/arrow/ipc"
+	"github.com/gin-gonic/gin"
+	"github.com/liweiyuan/arrow-interop/go-reveiver/schemaregistry"
+)
+
+// datasetHeader 是客户端用来标识数据集的请求头，schema registry 按它分区兼容性历史
+const datasetHeader = "X-Arrow-Dataset"
+
+// registry 是 /receive-arrow 和 /schemas 共用的 schema 注册表实例。默认是进程内的
+// schemaregistry.Registry；当设置了 ARROW_SCHEMA_REGISTRY_REDIS_ADDR 时，换成
+// schemaregistry.RedisRegistry，让多个接收端副本共享同一份当前 schema。
+var registry = newSchemaStore()
+
+// newSchemaStore 根据环境变量选择 schema registry 的后端实现
+func newSchemaStore() schemaregistry.Store {
+	if addr := os.Getenv("ARROW_SCHEMA_REGISTRY_REDIS_ADDR"); addr != "" {
+		hashKey := envOr("ARROW_SCHEMA_REGISTRY_REDIS_HASH", "arrow-schema-registry")
+		log.Printf("使用 Redis schema registry | addr: %s, hash: %s", addr, hashKey)
+		return schemaregistry.NewRedisRegistry(addr, hashKey, allocator)
+	}
+	return schemaregistry.New()
+}
+
+// registerSchemaResponse 是 POST /schemas/:dataset 的响应体
+type registerSchemaResponse struct {
+	Dataset       string `json:"dataset"`
+	SchemaID      string `json:"schema_id"`
+	Compatibility string `json:"compatibility"`
+}
+
+// handleRegisterSchema 从请求体里的 Arrow IPC stream 读取 schema，
+// 按 compatibility 查询参数（默认 BACKWARD）注册为 dataset 的当前 schema
+func handleRegisterSchema(c *gin.Context) {
+	dataset := c.Param("dataset")
+	defer c.Request.Body.Close()
+
+	reader, err := ipc.NewReader(c.Request.Body, ipc.WithAllocator(allocator))
+	if err != nil {
+		handleError(c, fmt.Errorf("创建 Arrow IPC reader 失败: %v", err))
+		return
+	}
+	defer reader.Release()
+
+	compat := schemaregistry.Compatibility(c.DefaultQuery("compatibility", string(schemaregistry.CompatBackward)))
+
+	id, err := registry.Register(c.Request.Context(), dataset, reader.Schema(), compat)
+	if err != nil {
+		handleError(c, fmt.Errorf("注册 schema 失败: %v", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, registerSchemaResponse{Dataset: dataset, SchemaID: id, Compatibility: string(compat)})
+}
+
+// handleGetSchema 返回 dataset 当前注册的 schema 及其兼容性策略
+func handleGetSchema(c *gin.Context) {
+	dataset := c.Param("dataset")
+
+	entry, ok, err := registry.Get(c.Request.Context(), dataset)
+	if err != nil {
+		handleError(c, fmt.Errorf("查询 schema 失败: %v", err))
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("数据集 %q 尚未注册 schema", dataset)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"dataset":       dataset,
+		"schema_id":     entry.ID,
+		"schema":        entry.Schema.String(),
+		"compatibility": entry.Compatibility,
+	})
+}
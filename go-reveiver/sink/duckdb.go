@@ -0,0 +1,177 @@
+package sink
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow/go/v18/arrow"
+	"github.com/apache/arrow/go/v18/arrow/array"
+	"github.com/marcboeker/go-duckdb"
+)
+
+// duckDBSink 把每个 record batch 通过 DuckDB Appender 逐行写入一张表。
+// go-duckdb 的 Appender 只暴露按行写入的 AppendRow，没有 Arrow record 级别的
+// 批量写入方法，所以这里把每一行的列值拆出来再追加。
+type duckDBSink struct {
+	db       *sql.DB
+	conn     driver.Conn
+	appender *duckdb.Appender
+}
+
+// newDuckDBSink 打开 dbPath 处的 DuckDB 数据库（":memory:" 为纯内存库），
+// 按 schema 建表（如果不存在）并创建一个绑定到 table 的 DuckDB Appender
+func newDuckDBSink(dbPath, table string, schema *arrow.Schema) (*duckDBSink, error) {
+	if table == "" {
+		return nil, fmt.Errorf("duckdb sink 需要一个目标表名")
+	}
+
+	connector, err := duckdb.NewConnector(dbPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建 DuckDB connector 失败: %v", err)
+	}
+	db := sql.OpenDB(connector)
+
+	ddl, err := duckDBCreateTableSQL(table, schema)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if _, err := db.Exec(ddl); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("创建 DuckDB 表失败: %v", err)
+	}
+
+	conn, err := connector.Connect(nil)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("获取 DuckDB 连接失败: %v", err)
+	}
+
+	appender, err := duckdb.NewAppenderFromConn(conn, "", table)
+	if err != nil {
+		conn.Close()
+		db.Close()
+		return nil, fmt.Errorf("创建 DuckDB appender 失败: %v", err)
+	}
+
+	return &duckDBSink{db: db, conn: conn, appender: appender}, nil
+}
+
+// Write 把一个 record batch 的所有行逐行追加到目标表
+func (s *duckDBSink) Write(record arrow.Record) error {
+	numRows := int(record.NumRows())
+	numCols := int(record.NumCols())
+
+	row := make([]driver.Value, numCols)
+	for i := 0; i < numRows; i++ {
+		for j := 0; j < numCols; j++ {
+			v, err := duckDBScalarValue(record.Column(j), i)
+			if err != nil {
+				return fmt.Errorf("提取第 %d 行第 %d 列的值失败: %v", i, j, err)
+			}
+			row[j] = v
+		}
+		if err := s.appender.AppendRow(row...); err != nil {
+			return fmt.Errorf("写入 DuckDB 失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// duckDBScalarValue 把 arr 在 rowIndex 处的值转换成 driver.Value，
+// 支持的类型和 duckDBTypeFor 的建表映射保持一致
+func duckDBScalarValue(arr arrow.Array, rowIndex int) (driver.Value, error) {
+	if arr.IsNull(rowIndex) {
+		return nil, nil
+	}
+
+	switch a := arr.(type) {
+	case *array.Int32:
+		return a.Value(rowIndex), nil
+	case *array.Int64:
+		return a.Value(rowIndex), nil
+	case *array.Uint32:
+		return a.Value(rowIndex), nil
+	case *array.Float32:
+		return a.Value(rowIndex), nil
+	case *array.Float64:
+		return a.Value(rowIndex), nil
+	case *array.String:
+		return a.Value(rowIndex), nil
+	case *array.LargeString:
+		return a.Value(rowIndex), nil
+	case *array.Boolean:
+		return a.Value(rowIndex), nil
+	case *array.Timestamp:
+		dt := arr.DataType().(*arrow.TimestampType)
+		return a.Value(rowIndex).ToTime(dt.Unit), nil
+	default:
+		return nil, fmt.Errorf("不支持写入 DuckDB 的 Arrow 类型: %T", arr)
+	}
+}
+
+// Close 依次刷新 appender、关闭 appender 借用的原生连接、再关闭连接池，
+// 顺序不能反：appender 必须先于它所绑定的 conn 被关闭
+func (s *duckDBSink) Close() error {
+	if err := s.appender.Close(); err != nil {
+		s.conn.Close()
+		s.db.Close()
+		return fmt.Errorf("关闭 DuckDB appender 失败: %v", err)
+	}
+	if err := s.conn.Close(); err != nil {
+		s.db.Close()
+		return fmt.Errorf("关闭 DuckDB 连接失败: %v", err)
+	}
+	return s.db.Close()
+}
+
+// duckDBCreateTableSQL 根据 Arrow schema 生成一个幂等的 CREATE TABLE IF NOT EXISTS 语句。
+// 表名和列名都来自客户端在 /receive-arrow 里提供的 schema，必须当作不可信输入处理，
+// 所以每个标识符都要用 quoteIdent 加引号，不能直接拼进 SQL 字符串。
+func duckDBCreateTableSQL(table string, schema *arrow.Schema) (string, error) {
+	stmt := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (", quoteIdent(table))
+	for i, f := range schema.Fields() {
+		if i > 0 {
+			stmt += ", "
+		}
+		sqlType, err := duckDBTypeFor(f.Type)
+		if err != nil {
+			return "", err
+		}
+		stmt += fmt.Sprintf("%s %s", quoteIdent(f.Name), sqlType)
+	}
+	stmt += ")"
+	return stmt, nil
+}
+
+// quoteIdent 把一个标识符包成 DuckDB 的双引号形式，并转义其中已有的双引号，
+// 防止客户端提供的字段名（例如 `id INTEGER); DROP TABLE x;--`）被当作 SQL 执行
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// duckDBTypeFor 把 Arrow 类型映射到对应的 DuckDB SQL 类型名
+func duckDBTypeFor(t arrow.DataType) (string, error) {
+	switch t.ID() {
+	case arrow.INT32:
+		return "INTEGER", nil
+	case arrow.INT64:
+		return "BIGINT", nil
+	case arrow.UINT32:
+		return "UINTEGER", nil
+	case arrow.FLOAT32:
+		return "FLOAT", nil
+	case arrow.FLOAT64:
+		return "DOUBLE", nil
+	case arrow.STRING, arrow.LARGE_STRING:
+		return "VARCHAR", nil
+	case arrow.BOOL:
+		return "BOOLEAN", nil
+	case arrow.TIMESTAMP:
+		return "TIMESTAMP", nil
+	default:
+		return "", fmt.Errorf("暂不支持映射到 DuckDB 的 Arrow 类型: %s", t)
+	}
+}
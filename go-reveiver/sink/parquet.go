@@ -0,0 +1,65 @@
+package sink
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/apache/arrow/go/v18/arrow"
+	"github.com/apache/arrow/go/v18/parquet"
+	"github.com/apache/arrow/go/v18/parquet/compress"
+	"github.com/apache/arrow/go/v18/parquet/pqarrow"
+)
+
+// parquetSink 把一次请求收到的所有 record batch 写成一个新的 Parquet row group 流。
+// Parquet 的 footer 只在文件关闭时写一次，没有“打开已有文件继续追加”的写法，所以
+// 每次请求都会在 ParquetDir 下创建一个独立的新文件，而不是复用/截断同一个共享路径。
+type parquetSink struct {
+	file   *os.File
+	writer *pqarrow.FileWriter
+}
+
+// newParquetSink 在 dir 目录下创建一个本次请求专属的 Parquet 文件，按 schema 初始化 writer
+func newParquetSink(dir string, schema *arrow.Schema) (*parquetSink, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("parquet sink 需要一个输出目录")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建 Parquet 输出目录失败: %v", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("batch-%d.parquet", time.Now().UnixNano()))
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("创建 Parquet 文件失败: %v", err)
+	}
+
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+	writer, err := pqarrow.NewFileWriter(schema, f, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("创建 Parquet writer 失败: %v", err)
+	}
+
+	return &parquetSink{file: f, writer: writer}, nil
+}
+
+// Write 把一个 record batch 作为一个 row group 写入 Parquet 文件
+func (s *parquetSink) Write(record arrow.Record) error {
+	if err := s.writer.WriteBuffered(record); err != nil {
+		return fmt.Errorf("写入 Parquet 失败: %v", err)
+	}
+	return nil
+}
+
+// Close 刷新 writer 并关闭底层文件
+func (s *parquetSink) Close() error {
+	if err := s.writer.Close(); err != nil {
+		s.file.Close()
+		return fmt.Errorf("关闭 Parquet writer 失败: %v", err)
+	}
+	return s.file.Close()
+}
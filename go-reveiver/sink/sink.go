@@ -0,0 +1,72 @@
+// Package sink 定义接收端可插拔的下游持久化目标（RecordSink），
+// 把 record batch 落地到 Parquet 文件、DuckDB 或 Kafka，而不是仅仅打印到标准输出。
+package sink
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow/go/v18/arrow"
+)
+
+// HeaderName 是客户端用来选择 sink 实现的 HTTP 请求头
+const HeaderName = "X-Arrow-Sink"
+
+// Kind 枚举当前支持的 sink 类型
+type Kind string
+
+const (
+	// KindNone 保持历史行为：只打印，不持久化
+	KindNone Kind = "none"
+	// KindParquet 把每个 record batch 追加写入一个 Parquet 文件
+	KindParquet Kind = "parquet"
+	// KindDuckDB 把每个 record batch 通过 DuckDB Appender 逐行写入 DuckDB 表
+	KindDuckDB Kind = "duckdb"
+	// KindKafka 把每个 record batch 编码为 Arrow IPC 消息并发布到 Kafka
+	KindKafka Kind = "kafka"
+)
+
+// RecordSink 是所有下游持久化目标的统一接口。
+// 实现必须是非阻塞安全的：Write 可能在同一个请求的多个批次间被反复调用，
+// Close 在请求处理完毕（或出错中止）时被调用一次，负责刷盘/释放底层资源。
+type RecordSink interface {
+	Write(record arrow.Record) error
+	Close() error
+}
+
+// Config 携带构造具体 sink 实现所需的连接信息
+type Config struct {
+	// ParquetDir 是 Parquet 文件的输出目录（KindParquet 使用）；每次请求都会在这个
+	// 目录下生成一个独立的新文件，而不是追加/截断同一个共享文件
+	ParquetDir string
+	// DuckDBPath 是 DuckDB 数据库文件路径，":memory:" 表示内存库（KindDuckDB 使用）
+	DuckDBPath string
+	// DuckDBTable 是写入的目标表名（KindDuckDB 使用）
+	DuckDBTable string
+	// KafkaBrokers 是 Kafka broker 地址列表（KindKafka 使用）
+	KafkaBrokers []string
+	// KafkaTopic 是发布消息的目标 topic（KindKafka 使用）
+	KafkaTopic string
+}
+
+// New 根据 kind 和 schema 构造对应的 RecordSink 实现。
+// schema 在构造时就需要确定，因为 Parquet/DuckDB 的底层 writer 都要求预先知道列定义。
+func New(kind Kind, schema *arrow.Schema, cfg Config) (RecordSink, error) {
+	switch kind {
+	case "", KindNone:
+		return noopSink{}, nil
+	case KindParquet:
+		return newParquetSink(cfg.ParquetDir, schema)
+	case KindDuckDB:
+		return newDuckDBSink(cfg.DuckDBPath, cfg.DuckDBTable, schema)
+	case KindKafka:
+		return newKafkaSink(cfg.KafkaBrokers, cfg.KafkaTopic, schema)
+	default:
+		return nil, fmt.Errorf("未知的 sink 类型: %s", kind)
+	}
+}
+
+// noopSink 保留原有的“只打印不持久化”行为，作为默认值
+type noopSink struct{}
+
+func (noopSink) Write(arrow.Record) error { return nil }
+func (noopSink) Close() error             { return nil }